@@ -0,0 +1,76 @@
+package content
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// cmsEntry is the shape a headless CMS is expected to return for a single
+// content item: frontmatter already split out as JSON, and the markdown
+// body untouched.
+type cmsEntry struct {
+	ID          string                 `json:"id"`
+	Frontmatter map[string]interface{} `json:"frontmatter"`
+	Markdown    string                 `json:"markdown"`
+}
+
+// CMSSource lists and opens content items from a headless CMS over HTTP.
+// BaseURL is expected to serve:
+//
+//	GET {BaseURL}/items        -> JSON array of cmsEntry (Markdown may be omitted)
+//	GET {BaseURL}/items/{id}   -> JSON cmsEntry
+type CMSSource struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewCMSSource(baseURL string) *CMSSource {
+	return &CMSSource{BaseURL: baseURL, Client: http.DefaultClient}
+}
+
+func (s *CMSSource) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return http.DefaultClient
+}
+
+func (s *CMSSource) List() ([]Item, error) {
+	resp, err := s.client().Get(s.BaseURL + "/items")
+	if err != nil {
+		return nil, fmt.Errorf("content: cms: listing items: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("content: cms: listing items: unexpected status %s", resp.Status)
+	}
+
+	var entries []cmsEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("content: cms: decoding item list: %v", err)
+	}
+
+	items := make([]Item, len(entries))
+	for i, entry := range entries {
+		items[i] = Item{ID: entry.ID}
+	}
+	return items, nil
+}
+
+func (s *CMSSource) Open(id string) (map[string]interface{}, []byte, error) {
+	resp, err := s.client().Get(s.BaseURL + "/items/" + id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("content: cms: opening item %s: %v", id, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("content: cms: opening item %s: unexpected status %s", id, resp.Status)
+	}
+
+	var entry cmsEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, nil, fmt.Errorf("content: cms: decoding item %s: %v", id, err)
+	}
+	return entry.Frontmatter, []byte(entry.Markdown), nil
+}
@@ -0,0 +1,41 @@
+// Package content abstracts where a site's pages come from, and where
+// their rendered output goes, behind a pair of small interfaces. This lets
+// blogdown build a site from something other than markdown files on disk —
+// a headless CMS, a database, an in-memory fixture for tests — without the
+// renderer or site packages needing to know the difference.
+package content
+
+import "time"
+
+// Item identifies a single piece of content a Source can produce, without
+// loading its body — enough for a caller to decide whether, and in what
+// order, to open it.
+type Item struct {
+	ID string
+	// ModTime is used as a sitemap lastmod fallback when a page has no
+	// "date" metadata field. Sources with no natural notion of
+	// modification time (e.g. a CMS that only versions by publish date)
+	// may leave this zero.
+	ModTime time.Time
+}
+
+// Source lists and opens content items from some backend.
+type Source interface {
+	List() ([]Item, error)
+	// Open returns the item's frontmatter metadata (if the source keeps it
+	// separate from the body, e.g. a CMS's JSON response) and its raw
+	// markdown body. A source with no separate metadata of its own (e.g.
+	// FilesystemSource, where frontmatter lives inside the markdown file)
+	// may return a nil map.
+	Open(id string) (metadata map[string]interface{}, body []byte, err error)
+}
+
+// Sink receives a rendered site's output.
+type Sink interface {
+	// Write stores a rendered page's output, keyed by its PageMeta.Path.
+	Write(pagePath string, output []byte) error
+	// WriteFile stores a top-level build artifact — e.g. sitemap.xml,
+	// feed.atom, chroma.css — by name, rather than nested under a page
+	// path like Write.
+	WriteFile(name string, output []byte) error
+}
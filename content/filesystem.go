@@ -0,0 +1,78 @@
+package content
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FilesystemSource lists and opens markdown files from a directory on
+// disk — the source blogdown has always read content from. Frontmatter
+// lives inside the markdown itself, so Open always returns a nil metadata
+// map and leaves extracting it to the parser.
+type FilesystemSource struct {
+	Dir string
+}
+
+func NewFilesystemSource(dir string) *FilesystemSource {
+	return &FilesystemSource{Dir: dir}
+}
+
+func (s *FilesystemSource) List() ([]Item, error) {
+	var items []Item
+	err := filepath.Walk(s.Dir, func(filePath string, info fs.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.IsDir() || !strings.HasSuffix(filePath, ".md") {
+			return nil
+		}
+		items = append(items, Item{ID: filePath, ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *FilesystemSource) Open(id string) (map[string]interface{}, []byte, error) {
+	body, err := ioutil.ReadFile(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, body, nil
+}
+
+// FilesystemSink writes rendered output to <OutDir>/<pagePath>/index.html,
+// matching blogdown's historical build/ layout.
+type FilesystemSink struct {
+	OutDir string
+}
+
+func NewFilesystemSink(outDir string) *FilesystemSink {
+	return &FilesystemSink{OutDir: outDir}
+}
+
+func (s *FilesystemSink) Write(pagePath string, output []byte) error {
+	dir := path.Join(s.OutDir, pagePath)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(path.Join(dir, "index.html"), output, 0644)
+}
+
+// WriteFile writes a top-level build artifact to <OutDir>/<name>.
+func (s *FilesystemSink) WriteFile(name string, output []byte) error {
+	if _, err := os.Stat(s.OutDir); os.IsNotExist(err) {
+		if err := os.MkdirAll(s.OutDir, 0755); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(path.Join(s.OutDir, name), output, 0644)
+}
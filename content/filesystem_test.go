@@ -0,0 +1,80 @@
+package content
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestFilesystemSourceListFindsMarkdownFiles(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite := func(relPath, content string) {
+		full := filepath.Join(dir, relPath)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := ioutil.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	mustWrite("hello.md", "# Hello")
+	mustWrite("posts/world.md", "# World")
+	mustWrite("notes.txt", "not markdown")
+
+	items, err := NewFilesystemSource(dir).List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+		if item.ModTime.IsZero() {
+			t.Errorf("item %s has zero ModTime", item.ID)
+		}
+	}
+	sort.Strings(ids)
+
+	want := []string{
+		filepath.Join(dir, "hello.md"),
+		filepath.Join(dir, "posts/world.md"),
+	}
+	sort.Strings(want)
+
+	if len(ids) != len(want) {
+		t.Fatalf("List returned %v, want %v", ids, want)
+	}
+	for i := range ids {
+		if ids[i] != want[i] {
+			t.Errorf("List()[%d] = %q, want %q", i, ids[i], want[i])
+		}
+	}
+}
+
+func TestFilesystemSourceOpenReturnsBodyWithNilMetadata(t *testing.T) {
+	dir := t.TempDir()
+	full := filepath.Join(dir, "hello.md")
+	if err := ioutil.WriteFile(full, []byte("# Hello"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	metadata, body, err := NewFilesystemSource(dir).Open(full)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if metadata != nil {
+		t.Errorf("metadata = %v, want nil (frontmatter lives in the markdown body)", metadata)
+	}
+	if string(body) != "# Hello" {
+		t.Errorf("body = %q, want %q", body, "# Hello")
+	}
+}
+
+func TestFilesystemSourceOpenMissingFile(t *testing.T) {
+	_, _, err := NewFilesystemSource(t.TempDir()).Open("does-not-exist.md")
+	if err == nil {
+		t.Fatal("Open: expected an error for a missing file, got nil")
+	}
+}
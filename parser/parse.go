@@ -28,7 +28,7 @@ func NewParser() Parser {
 	}
 }
 
-// Parse file takes in a markdown file and parses it into a node tree and corresponding metadata
+// ParseFile takes in a markdown file and parses it into a node tree and corresponding metadata
 func (p *Parser) ParseFile(filepath string) (map[string]interface{}, ast.Node, []byte, error) {
 	if !strings.HasSuffix(filepath, ".md") {
 		return nil, nil, nil, fmt.Errorf("parsing error: input file must be a markdown file with the '.md' extension")
@@ -38,12 +38,20 @@ func (p *Parser) ParseFile(filepath string) (map[string]interface{}, ast.Node, [
 		return nil, nil, nil, fmt.Errorf("parsing error: %v", err)
 	}
 
+	return p.Parse(mdFileContent)
+}
+
+// Parse parses raw markdown content into a node tree and corresponding
+// metadata, without requiring that it came from a file. This lets callers
+// whose content comes from somewhere other than disk (e.g. a CMS) reuse the
+// same parsing logic as ParseFile.
+func (p *Parser) Parse(body []byte) (map[string]interface{}, ast.Node, []byte, error) {
 	metaContext := parser.NewContext()
-	documentNode := p.md.Parser().Parse(text.NewReader(mdFileContent), parser.WithContext(metaContext))
+	documentNode := p.md.Parser().Parse(text.NewReader(body), parser.WithContext(metaContext))
 
 	metadata, err := meta.TryGet(metaContext)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("parsing error: error getting metadata: %v", err)
 	}
-	return metadata, documentNode, mdFileContent, nil
+	return metadata, documentNode, body, nil
 }
@@ -0,0 +1,130 @@
+package renderer
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// chromaCSSMu guards writeChromaCSS, since top-level blocks (including
+// fenced code blocks) render concurrently on renderBlocksParallel's worker
+// pool and would otherwise race writing the single shared chroma.css.
+var chromaCSSMu sync.Mutex
+
+// highlightFencedCodeBlock renders source as HTML using Chroma. Site-wide
+// appearance is driven by a handful of keys read from the site config map:
+//   - "chroma.style": chroma style name (defaults to "github")
+//   - "chroma.lineNumbers": bool, render line numbers
+//   - "chroma.tabWidth": int, width of a tab character (defaults to 4)
+//   - "chroma.noClasses": bool, inline styles instead of CSS classes
+//
+// highlightLines is per-block rather than site-wide, since different
+// snippets on the same page commonly need different lines highlighted; see
+// parseHighlightLines for where it comes from. When CSS classes are used,
+// the stylesheet is (re)written to chroma.css via sink.
+func highlightFencedCodeBlock(source string, language string, highlightLines [][2]int, siteConfig map[string]interface{}, sink Sink) (string, error) {
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	styleName, _ := siteConfig["chroma.style"].(string)
+	if styleName == "" {
+		styleName = "github"
+	}
+	style := styles.Get(styleName)
+	if style == nil {
+		style = styles.Fallback
+	}
+
+	tabWidth := 4
+	if configuredTabWidth, ok := siteConfig["chroma.tabWidth"].(int); ok && configuredTabWidth > 0 {
+		tabWidth = configuredTabWidth
+	}
+
+	noClasses, _ := siteConfig["chroma.noClasses"].(bool)
+	formatterOptions := []chromahtml.Option{
+		chromahtml.TabWidth(tabWidth),
+		chromahtml.WithClasses(!noClasses),
+	}
+	if lineNumbers, ok := siteConfig["chroma.lineNumbers"].(bool); ok && lineNumbers {
+		formatterOptions = append(formatterOptions, chromahtml.WithLineNumbers(true))
+	}
+	if len(highlightLines) > 0 {
+		formatterOptions = append(formatterOptions, chromahtml.HighlightLines(highlightLines))
+	}
+	formatter := chromahtml.New(formatterOptions...)
+
+	iterator, err := lexer.Tokenise(nil, source)
+	if err != nil {
+		return "", err
+	}
+
+	var outputBuffer bytes.Buffer
+	if err := formatter.Format(&outputBuffer, style, iterator); err != nil {
+		return "", err
+	}
+
+	if !noClasses {
+		if err := writeChromaCSS(formatter, style, sink); err != nil {
+			return "", err
+		}
+	}
+
+	return outputBuffer.String(), nil
+}
+
+// parseHighlightLines reads per-block highlight ranges out of a fenced code
+// block's info string, e.g. "go hl:2,5-7" highlights line 2 and lines 5
+// through 7. Unrecognized or malformed tokens are ignored.
+func parseHighlightLines(info string) [][2]int {
+	var ranges [][2]int
+	for _, field := range strings.Fields(info) {
+		if !strings.HasPrefix(field, "hl:") {
+			continue
+		}
+		spec := strings.TrimPrefix(field, "hl:")
+		for _, part := range strings.Split(spec, ",") {
+			start, end, ok := parseLineRange(part)
+			if !ok {
+				continue
+			}
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+	return ranges
+}
+
+func parseLineRange(part string) (start, end int, ok bool) {
+	if dash := strings.IndexByte(part, '-'); dash >= 0 {
+		start, err1 := strconv.Atoi(part[:dash])
+		end, err2 := strconv.Atoi(part[dash+1:])
+		if err1 != nil || err2 != nil || start <= 0 || end < start {
+			return 0, 0, false
+		}
+		return start, end, true
+	}
+	line, err := strconv.Atoi(part)
+	if err != nil || line <= 0 {
+		return 0, 0, false
+	}
+	return line, line, true
+}
+
+func writeChromaCSS(formatter *chromahtml.Formatter, style *chroma.Style, sink Sink) error {
+	chromaCSSMu.Lock()
+	defer chromaCSSMu.Unlock()
+
+	var cssBuffer bytes.Buffer
+	if err := formatter.WriteCSS(&cssBuffer, style); err != nil {
+		return err
+	}
+	return sink.WriteFile("chroma.css", cssBuffer.Bytes())
+}
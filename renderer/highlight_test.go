@@ -0,0 +1,113 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHighlightLines(t *testing.T) {
+	tests := []struct {
+		name string
+		info string
+		want [][2]int
+	}{
+		{name: "no hl token", info: "go", want: nil},
+		{name: "single line", info: "go hl:2", want: [][2]int{{2, 2}}},
+		{name: "range", info: "go hl:5-7", want: [][2]int{{5, 7}}},
+		{name: "multiple", info: "go hl:2,5-7", want: [][2]int{{2, 2}, {5, 7}}},
+		{name: "empty spec", info: "go hl:", want: nil},
+		{name: "non-numeric ignored", info: "go hl:abc", want: nil},
+		{name: "inverted range ignored", info: "go hl:7-5", want: nil},
+		{name: "zero ignored", info: "go hl:0", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseHighlightLines(tt.info)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseHighlightLines(%q) = %v, want %v", tt.info, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("parseHighlightLines(%q)[%d] = %v, want %v", tt.info, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// capturingSink records WriteFile calls so tests can assert whether and what
+// highlightFencedCodeBlock wrote for chroma.css.
+type capturingSink struct {
+	discardSink
+	written map[string][]byte
+}
+
+func (s *capturingSink) WriteFile(name string, output []byte) error {
+	if s.written == nil {
+		s.written = make(map[string][]byte)
+	}
+	s.written[name] = output
+	return nil
+}
+
+func TestHighlightFencedCodeBlockNoClassesSkipsCSS(t *testing.T) {
+	sink := &capturingSink{}
+	output, err := highlightFencedCodeBlock("x := 1\n", "go", nil, map[string]interface{}{"chroma.noClasses": true}, sink)
+	if err != nil {
+		t.Fatalf("highlightFencedCodeBlock: %v", err)
+	}
+	if strings.Contains(output, `class="chroma"`) {
+		t.Errorf("output = %q, want inline styles (no chroma class) when chroma.noClasses is set", output)
+	}
+	if _, wrote := sink.written["chroma.css"]; wrote {
+		t.Errorf("chroma.css was written even though chroma.noClasses was set")
+	}
+}
+
+func TestHighlightFencedCodeBlockClassesWritesCSS(t *testing.T) {
+	sink := &capturingSink{}
+	_, err := highlightFencedCodeBlock("x := 1\n", "go", nil, map[string]interface{}{}, sink)
+	if err != nil {
+		t.Fatalf("highlightFencedCodeBlock: %v", err)
+	}
+	if _, wrote := sink.written["chroma.css"]; !wrote {
+		t.Errorf("chroma.css was not written in the default (class-based) mode")
+	}
+}
+
+func TestHighlightFencedCodeBlockLineNumbers(t *testing.T) {
+	sink := &capturingSink{}
+	output, err := highlightFencedCodeBlock("x := 1\n", "go", nil, map[string]interface{}{"chroma.lineNumbers": true}, sink)
+	if err != nil {
+		t.Fatalf("highlightFencedCodeBlock: %v", err)
+	}
+	if !strings.Contains(output, `class="ln"`) {
+		t.Errorf("output = %q, want line-number markup when chroma.lineNumbers is set", output)
+	}
+}
+
+func TestHighlightFencedCodeBlockStyleSelection(t *testing.T) {
+	sinkA := &capturingSink{}
+	if _, err := highlightFencedCodeBlock("x := 1\n", "go", nil, map[string]interface{}{"chroma.style": "github"}, sinkA); err != nil {
+		t.Fatalf("highlightFencedCodeBlock(github): %v", err)
+	}
+	sinkB := &capturingSink{}
+	if _, err := highlightFencedCodeBlock("x := 1\n", "go", nil, map[string]interface{}{"chroma.style": "monokai"}, sinkB); err != nil {
+		t.Fatalf("highlightFencedCodeBlock(monokai): %v", err)
+	}
+	if string(sinkA.written["chroma.css"]) == string(sinkB.written["chroma.css"]) {
+		t.Errorf("expected different chroma.style values to produce different chroma.css output")
+	}
+}
+
+func TestHighlightFencedCodeBlockHighlightLinesPerBlock(t *testing.T) {
+	sink := &capturingSink{}
+	output, err := highlightFencedCodeBlock("a := 1\nb := 2\nc := 3\n", "go", [][2]int{{2, 2}}, map[string]interface{}{}, sink)
+	if err != nil {
+		t.Fatalf("highlightFencedCodeBlock: %v", err)
+	}
+	if !strings.Contains(output, "hl") {
+		t.Errorf("output = %q, want a highlighted-line marker when highlightLines is set", output)
+	}
+}
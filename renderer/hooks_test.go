@@ -0,0 +1,25 @@
+package renderer
+
+import "testing"
+
+func TestClassifyLinkDestination(t *testing.T) {
+	tests := []struct {
+		destination string
+		want        string
+	}{
+		{"https://example.com/post", "external"},
+		{"http://example.com", "external"},
+		{"//example.com/post", "external"},
+		{"/posts/hello", "internal"},
+		{"/", "internal"},
+		{"hello.md", "ref"},
+		{"../hello.md", "ref"},
+		{"#section", "ref"},
+	}
+
+	for _, tt := range tests {
+		if got := classifyLinkDestination(tt.destination); got != tt.want {
+			t.Errorf("classifyLinkDestination(%q) = %q, want %q", tt.destination, got, tt.want)
+		}
+	}
+}
@@ -3,51 +3,27 @@ package renderer
 import (
 	"bytes"
 	"fmt"
+	htmlstd "html"
 	"io/ioutil"
 	"os"
 	"path"
 	"runtime"
 	"strings"
+	"sync"
 	"text/template"
 
+	"github.com/alecthomas/chroma/v2/lexers"
 	"github.com/yuin/goldmark/ast"
 )
 
-const (
-	// Block Templates
-	DocumentTemplatePath        = "../templates/block/document.tmpl.html"
-	TextBlockTemplatePath       = "../templates/block/text-block.tmpl.html"
-	ParagraphTemplatePath       = "../templates/block/paragraph.tmpl.html"
-	HeadingTemplatePath         = "../templates/block/heading.tmpl.html"
-	ThematicBreakTemplatePath   = "../templates/block/thematic-break.tmpl.html"
-	CodeBlockTemplatePath       = "../templates/block/code-block.tmpl.html"
-	FencedCodeBlockTemplatePath = "../templates/block/fenced-code-block.tmpl.html"
-	BlockquoteTemplatePath      = "../templates/block/blockquote.tmpl.html"
-	ListTemplatePath            = "../templates/block/list.tmpl.html"
-	ListItemTemplatePath        = "../templates/block/list-item.tmpl.html"
-	HTMLBlockTemplatePath       = "../templates/block/html-block.tmpl.html"
-
-	// Inline Templates
-	TextTemplatePath     = "../templates/inline/text.tmpl.html"
-	StringTemplatePath   = "../templates/inline/string.tmpl.html"
-	CodeSpanTemplatePath = "../templates/inline/code-span.tmpl.html"
-	EmphasisTemplatePath = "../templates/inline/emphasis.tmpl.html"
-	LinkTemplatePath     = "../templates/inline/link.tmpl.html"
-	ImageTemplatePath    = "../templates/inline/image.tmpl.html"
-	AutoLinkTemplatePath = "../templates/inline/auto-link.tmpl.html"
-	RawHTMLTemplatePath  = "../templates/inline/raw-html.tmpl.html"
-)
-
-var (
-	// Map Templates to AST Types
-	KindTemplateMap map[ast.NodeKind]string
-)
-
 type PageMeta struct {
 	Title       string
 	Description string
 	Slug        string
 	Path        string
+	// Section is the page's "section" metadata field, if any. It selects
+	// which base layout a page is composed into; see loadBaseLayoutContent.
+	Section string
 }
 
 type TemplateData struct {
@@ -55,119 +31,444 @@ type TemplateData struct {
 	Config   map[string]interface{}
 	Content  string
 	Children string
+	Hook     *HookData
+}
+
+// HookData is passed to user-supplied render-hook templates (see
+// hookTemplateRelPath) in addition to the usual TemplateData fields, giving
+// them access to richer context than Content/Children alone provide.
+type HookData struct {
+	Destination string
+	Title       string
+	Label       string
+	// LinkKind is only set for ast.KindLink and ast.KindAutoLink nodes, and
+	// is one of "external", "internal", or "ref".
+	LinkKind string
 }
 
-func InitKindTemplateMap() {
-	rendererPath := "./"
-	_, filename, _, ok := runtime.Caller(0)
-	if ok {
-		rendererPath = path.Dir(filename)
-	}
-	KindTemplateMap = make(map[ast.NodeKind]string)
-	KindTemplateMap[ast.KindDocument] = path.Join(rendererPath, DocumentTemplatePath)
-	KindTemplateMap[ast.KindTextBlock] = path.Join(rendererPath, TextBlockTemplatePath)
-	KindTemplateMap[ast.KindParagraph] = path.Join(rendererPath, ParagraphTemplatePath)
-	KindTemplateMap[ast.KindHeading] = path.Join(rendererPath, HeadingTemplatePath)
-	KindTemplateMap[ast.KindThematicBreak] = path.Join(rendererPath, ThematicBreakTemplatePath)
-	KindTemplateMap[ast.KindCodeBlock] = path.Join(rendererPath, CodeBlockTemplatePath)
-	KindTemplateMap[ast.KindFencedCodeBlock] = path.Join(rendererPath, FencedCodeBlockTemplatePath)
-	KindTemplateMap[ast.KindBlockquote] = path.Join(rendererPath, BlockquoteTemplatePath)
-	KindTemplateMap[ast.KindList] = path.Join(rendererPath, ListTemplatePath)
-	KindTemplateMap[ast.KindListItem] = path.Join(rendererPath, ListItemTemplatePath)
-	KindTemplateMap[ast.KindHTMLBlock] = path.Join(rendererPath, HTMLBlockTemplatePath)
-	KindTemplateMap[ast.KindText] = path.Join(rendererPath, TextTemplatePath)
-	KindTemplateMap[ast.KindString] = path.Join(rendererPath, StringTemplatePath)
-	KindTemplateMap[ast.KindCodeSpan] = path.Join(rendererPath, CodeSpanTemplatePath)
-	KindTemplateMap[ast.KindEmphasis] = path.Join(rendererPath, EmphasisTemplatePath)
-	KindTemplateMap[ast.KindLink] = path.Join(rendererPath, LinkTemplatePath)
-	KindTemplateMap[ast.KindImage] = path.Join(rendererPath, ImageTemplatePath)
-	KindTemplateMap[ast.KindAutoLink] = path.Join(rendererPath, AutoLinkTemplatePath)
-	KindTemplateMap[ast.KindRawHTML] = path.Join(rendererPath, RawHTMLTemplatePath)
-}
-
-func RenderAst(pageMetadata map[string]interface{}, documentNode ast.Node, inputFileBytes []byte) error {
+// NewPageMeta extracts the fields a Renderer needs from a page's raw
+// frontmatter metadata map.
+func NewPageMeta(pageMetadata map[string]interface{}) (PageMeta, error) {
 	pageTitleInt, exists := pageMetadata["title"]
 	if !exists {
-		return fmt.Errorf("rendering error: page does not contain \"title\" in metadata")
+		return PageMeta{}, fmt.Errorf("rendering error: page does not contain \"title\" in metadata")
 	}
 	pageTitle := fmt.Sprintf("%s", pageTitleInt)
 	pageDescriptionInt, exists := pageMetadata["description"]
 	if !exists {
-		return fmt.Errorf("rendering error: page does not contain \"description\" in metadata")
+		return PageMeta{}, fmt.Errorf("rendering error: page does not contain \"description\" in metadata")
 	}
 	pageDescription := fmt.Sprintf("%s", pageDescriptionInt)
 	pageSlugInt, exists := pageMetadata["slug"]
 	if !exists {
-		return fmt.Errorf("rendering error: page does not contain \"slug\" in metadata")
+		return PageMeta{}, fmt.Errorf("rendering error: page does not contain \"slug\" in metadata")
 	}
 	pageSlug := fmt.Sprintf("%s", pageSlugInt)
 	pagePathInt, exists := pageMetadata["path"]
 	if !exists {
-		return fmt.Errorf("rendering error: page does not contain \"path\" in metadata")
+		return PageMeta{}, fmt.Errorf("rendering error: page does not contain \"path\" in metadata")
 	}
 	pagePath := fmt.Sprintf("%s", pagePathInt)
 	if !strings.HasPrefix(pagePath, "/") {
-		return fmt.Errorf("rendering error: page path doesn't being with a \"/\" (forward slash)")
+		return PageMeta{}, fmt.Errorf("rendering error: page path doesn't being with a \"/\" (forward slash)")
 	}
 
-	pageMeta := PageMeta{
+	pageSection := ""
+	if pageSectionInt, exists := pageMetadata["section"]; exists {
+		pageSection = fmt.Sprintf("%s", pageSectionInt)
+	}
+
+	return PageMeta{
 		Title:       pageTitle,
 		Description: pageDescription,
 		Slug:        pageSlug,
-		Path:        DocumentTemplatePath,
+		Path:        pagePath,
+		Section:     pageSection,
+	}, nil
+}
+
+// Options configures a Renderer.
+type Options struct {
+	// LayoutsDir is a project-local directory that, when it contains a file
+	// at the same relative path as one of the paths in KindTemplateMap,
+	// overrides the corresponding embedded template. This lets a site theme
+	// blogdown without forking it. Defaults to "layouts".
+	LayoutsDir string
+	// SiteConfig is passed through as the base layout's TemplateData.Config
+	// and consulted directly for features, like Chroma highlighting, that are
+	// configured site-wide rather than per-node. Per-node templates receive
+	// their own node-specific Config (e.g. "level", "tagType") instead.
+	SiteConfig map[string]interface{}
+	// Concurrency bounds how many blocks are rendered at once. Defaults to
+	// runtime.NumCPU().
+	Concurrency int
+}
+
+// Renderer turns a parsed markdown document into HTML. Its per-kind
+// templates, with layout overrides applied, are parsed once at construction;
+// render hooks and base layouts are parsed on first use and cached. A
+// Renderer should be built once per build and reused across all of a site's
+// pages rather than recreated per page.
+type Renderer struct {
+	layoutsDir  string
+	siteConfig  map[string]interface{}
+	concurrency int
+
+	nodeTemplates map[ast.NodeKind]*template.Template
+
+	hookMu        sync.Mutex
+	hookTemplates map[string]*template.Template
+	hookMisses    map[string]bool
+
+	baseMu        sync.Mutex
+	baseTemplates map[string]*template.Template
+}
+
+// NewRenderer parses the full template set — every kind in KindTemplateMap,
+// honoring layout overrides — once, up front.
+func NewRenderer(opts Options) (*Renderer, error) {
+	layoutsDir := opts.LayoutsDir
+	if layoutsDir == "" {
+		layoutsDir = "layouts"
 	}
-	output, err := renderAstNode(pageMeta, documentNode, inputFileBytes)
-	if err != nil {
-		return err
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
 	}
 
-	if _, err := os.Stat(path.Join("build", pagePath)); os.IsNotExist(err) {
-		err := os.MkdirAll(path.Join("build", pagePath), os.ModeDir)
+	r := &Renderer{
+		layoutsDir:    layoutsDir,
+		siteConfig:    opts.SiteConfig,
+		concurrency:   concurrency,
+		hookTemplates: make(map[string]*template.Template),
+		hookMisses:    make(map[string]bool),
+		baseTemplates: make(map[string]*template.Template),
+	}
+
+	if len(KindTemplateMap) == 0 {
+		InitKindTemplateMap()
+	}
+
+	nodeTemplates := make(map[ast.NodeKind]*template.Template, len(KindTemplateMap))
+	for kind, relPath := range KindTemplateMap {
+		content, err := r.loadTemplateContent(relPath)
+		if err != nil {
+			return nil, err
+		}
+		t, err := template.New(relPath).Parse(content)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("rendering error: while parsing template %s: %v", relPath, err)
 		}
+		nodeTemplates[kind] = t
+	}
+	r.nodeTemplates = nodeTemplates
+
+	return r, nil
+}
+
+// Sink receives a rendered site's output. It's declared here structurally
+// rather than imported from the content package so that renderer has no
+// dependency on where pages come from or go — any type satisfying this
+// method set works, including content.Sink.
+type Sink interface {
+	// Write stores a rendered page's output, keyed by its PageMeta.Path.
+	Write(pagePath string, output []byte) error
+	// WriteFile stores a top-level build artifact, such as chroma.css, by
+	// name rather than nested under a page path like Write.
+	WriteFile(name string, output []byte) error
+}
+
+// RenderPage renders documentNode, composed into the page's base layout, and
+// hands the result to sink under pageMeta.Path.
+func (r *Renderer) RenderPage(pageMetadata map[string]interface{}, documentNode ast.Node, inputFileBytes []byte, sink Sink) error {
+	pageMeta, err := NewPageMeta(pageMetadata)
+	if err != nil {
+		return err
+	}
+
+	blocksContent, err := r.renderBlocksParallel(pageMeta, siblingSlice(documentNode.FirstChild()), inputFileBytes, sink)
+	if err != nil {
+		return err
+	}
+	body, err := r.renderNode(pageMeta, documentNode, blocksContent, inputFileBytes, sink)
+	if err != nil {
+		return err
 	}
 
-	err = ioutil.WriteFile(path.Join("build", pagePath, "/index.html"), []byte(output), os.ModeAppend)
+	output, err := r.renderWithBaseLayout(pageMeta, body)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	return sink.Write(pageMeta.Path, []byte(output))
+}
+
+// RenderFragment renders n and its descendants in isolation — n's own
+// following siblings are never consulted. It's exported for callers, like
+// the site package's feed summaries, that want a single fragment of a
+// document rendered on its own. sink is only consulted if the fragment
+// contains a fenced code block that needs to (re)write chroma.css.
+func (r *Renderer) RenderFragment(pageMeta PageMeta, n ast.Node, inputFileBytes []byte, sink Sink) (string, error) {
+	return r.renderSubtree(pageMeta, n, inputFileBytes, sink)
 }
 
-func renderAstNode(pageMetadata PageMeta, n ast.Node, inputFileBytes []byte) (output string, err error) {
-	firstChildContent := ""
+// renderSubtree renders n, descending into n's children (if any) by walking
+// them in a loop rather than tail-recursing through NextSibling, so a long
+// run of siblings (e.g. a list with thousands of items) doesn't blow the
+// stack.
+func (r *Renderer) renderSubtree(pageMeta PageMeta, n ast.Node, inputFileBytes []byte, sink Sink) (string, error) {
+	childrenContent := ""
 	if n.HasChildren() {
-		firstChildContent, err = renderAstNode(pageMetadata, n.FirstChild(), inputFileBytes)
+		content, err := r.renderChildrenSequential(pageMeta, n.FirstChild(), inputFileBytes, sink)
+		if err != nil {
+			return "", err
+		}
+		childrenContent = content
+	}
+	return r.renderNode(pageMeta, n, childrenContent, inputFileBytes, sink)
+}
+
+func (r *Renderer) renderChildrenSequential(pageMeta PageMeta, firstChild ast.Node, inputFileBytes []byte, sink Sink) (string, error) {
+	var outputs []string
+	for cur := firstChild; cur != nil; cur = cur.NextSibling() {
+		output, err := r.renderSubtree(pageMeta, cur, inputFileBytes, sink)
+		if err != nil {
+			return "", err
+		}
+		outputs = append(outputs, output)
+	}
+	return strings.Join(outputs, "\n"), nil
+}
+
+// renderBlocksParallel renders each of blocks independently on a worker
+// pool bounded by r.concurrency, then joins the results back together in
+// document order. It's used for a document's top-level blocks, which don't
+// depend on one another.
+func (r *Renderer) renderBlocksParallel(pageMeta PageMeta, blocks []ast.Node, inputFileBytes []byte, sink Sink) (string, error) {
+	outputs := make([]string, len(blocks))
+	errs := make([]error, len(blocks))
+
+	sem := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+	for i, block := range blocks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, block ast.Node) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			outputs[i], errs[i] = r.renderSubtree(pageMeta, block, inputFileBytes, sink)
+		}(i, block)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
 		if err != nil {
-			return
+			return "", err
 		}
 	}
-	templateContent, err := getTemplateContent(n.Kind())
+	return strings.Join(outputs, "\n"), nil
+}
+
+// siblingSlice collects n and its following siblings into a slice, so
+// callers can walk or fan them out without recursing through NextSibling.
+func siblingSlice(n ast.Node) []ast.Node {
+	nodes := make([]ast.Node, 0)
+	for cur := n; cur != nil; cur = cur.NextSibling() {
+		nodes = append(nodes, cur)
+	}
+	return nodes
+}
+
+func (r *Renderer) renderNode(pageMeta PageMeta, n ast.Node, childrenContent string, inputFileBytes []byte, sink Sink) (string, error) {
+	hookData := buildHookData(n, inputFileBytes)
+	tmpl, err := r.templateFor(n.Kind(), hookData)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := generateTemplateDataContent(n, inputFileBytes, r.siteConfig, sink)
 	if err != nil {
-		return
-	}
-	templateConfig := generateTemplateDataConfig(n)
-	output, err = renderTemplateToString(templateContent, TemplateData{
-		Meta:     pageMetadata,
-		Config:   templateConfig,
-		Content:  generateTemplateDataContent(n, inputFileBytes),
-		Children: firstChildContent,
+		return "", err
+	}
+
+	var outputBuffer bytes.Buffer
+	err = tmpl.Execute(&outputBuffer, TemplateData{
+		Meta:     pageMeta,
+		Config:   generateTemplateDataConfig(n),
+		Content:  content,
+		Children: childrenContent,
+		Hook:     hookData,
 	})
 	if err != nil {
-		return
+		return "", err
 	}
-	if n.NextSibling() != nil {
-		nextSiblingContent, err := renderAstNode(pageMetadata, n.NextSibling(), inputFileBytes)
+	return outputBuffer.String(), nil
+}
+
+// templateFor resolves the template to use for kind, preferring a render
+// hook (see hookTemplate) over the regular per-kind template.
+func (r *Renderer) templateFor(kind ast.NodeKind, hookData *HookData) (*template.Template, error) {
+	if _, supportsHook := hookTemplateRelPath[kind]; supportsHook {
+		t, err := r.hookTemplate(kind, hookData)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		output = fmt.Sprintf("%s\n%s", output, nextSiblingContent)
+		if t != nil {
+			return t, nil
+		}
+	}
+
+	t, exists := r.nodeTemplates[kind]
+	if !exists {
+		return nil, fmt.Errorf("rendering error: node kind (%s) doesn't have a template assigned to it", kind)
+	}
+	return t, nil
+}
+
+// hookTemplate looks for a user-supplied render-hook template for kind,
+// parsing and caching it on first use. Links are additionally looked up by
+// hookData.LinkKind (e.g. "_markup/render-link-external.html") before
+// falling back to the kind-generic hook. Returns (nil, nil) when no hook
+// template is present, so the caller can fall back to the regular built-in
+// template.
+func (r *Renderer) hookTemplate(kind ast.NodeKind, hookData *HookData) (*template.Template, error) {
+	relPath := hookTemplateRelPath[kind]
+	candidates := make([]string, 0, 2)
+	if kind == ast.KindLink && hookData != nil {
+		withoutExt := strings.TrimSuffix(relPath, ".html")
+		candidates = append(candidates, fmt.Sprintf("%s-%s.html", withoutExt, hookData.LinkKind))
+	}
+	candidates = append(candidates, relPath)
+
+	for _, candidate := range candidates {
+		overridePath := path.Join(r.layoutsDir, candidate)
+
+		r.hookMu.Lock()
+		cached, found := r.hookTemplates[overridePath]
+		missed := r.hookMisses[overridePath]
+		r.hookMu.Unlock()
+		if found {
+			return cached, nil
+		}
+		if missed {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(overridePath)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("rendering error: while reading render hook %s: %v", overridePath, err)
+			}
+			r.hookMu.Lock()
+			r.hookMisses[overridePath] = true
+			r.hookMu.Unlock()
+			continue
+		}
+
+		t, err := template.New(overridePath).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("rendering error: while parsing render hook %s: %v", overridePath, err)
+		}
+		r.hookMu.Lock()
+		r.hookTemplates[overridePath] = t
+		r.hookMu.Unlock()
+		return t, nil
+	}
+	return nil, nil
+}
+
+// loadTemplateContent reads relPath from the layouts override directory,
+// falling back to the embedded default.
+func (r *Renderer) loadTemplateContent(relPath string) (string, error) {
+	overridePath := path.Join(r.layoutsDir, relPath)
+	if content, err := ioutil.ReadFile(overridePath); err == nil {
+		return string(content), nil
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("rendering error: while reading layout override %s: %v", overridePath, err)
+	}
+
+	content, err := embeddedTemplates.ReadFile(path.Join(templatesRoot, relPath))
+	if err != nil {
+		return "", fmt.Errorf("rendering error: while reading template %s: %v", relPath, err)
+	}
+	return string(content), nil
+}
+
+// renderWithBaseLayout composes an already-rendered page body into the base
+// layout's "main" block and executes the result as a whole, giving the page
+// a consistent <html>/<head>/<body> shell.
+//
+// bodyHTML is passed through as data (TemplateData.Content), never as
+// template source: the page body can contain arbitrary user content (a post
+// about Go templates, a fenced code block with literal "{{"), and re-parsing
+// it as a template would let that content execute as template actions with
+// access to Meta/Config.
+func (r *Renderer) renderWithBaseLayout(pageMeta PageMeta, bodyHTML string) (string, error) {
+	base, err := r.baseLayoutTemplate(pageMeta.Section)
+	if err != nil {
+		return "", err
+	}
+
+	var outputBuffer bytes.Buffer
+	err = base.ExecuteTemplate(&outputBuffer, "baseof", TemplateData{
+		Meta:    pageMeta,
+		Config:  r.siteConfig,
+		Content: bodyHTML,
+	})
+	if err != nil {
+		return "", fmt.Errorf("rendering error: while executing base layout: %v", err)
+	}
+	return outputBuffer.String(), nil
+}
+
+// baseLayoutTemplate resolves and caches the base layout for section: a
+// section-specific override takes priority over the site-wide default
+// override, which in turn takes priority over the embedded default.
+//
+//	layouts/<section>/baseof.html -> layouts/_default/baseof.html -> embedded
+func (r *Renderer) baseLayoutTemplate(section string) (*template.Template, error) {
+	r.baseMu.Lock()
+	defer r.baseMu.Unlock()
+	if t, ok := r.baseTemplates[section]; ok {
+		return t, nil
+	}
+
+	content, err := r.loadBaseLayoutContent(section)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New("baseof").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("rendering error: while parsing base layout: %v", err)
+	}
+	r.baseTemplates[section] = t
+	return t, nil
+}
+
+func (r *Renderer) loadBaseLayoutContent(section string) (string, error) {
+	candidates := make([]string, 0, 2)
+	if section != "" {
+		candidates = append(candidates, path.Join(r.layoutsDir, section, "baseof.html"))
+	}
+	candidates = append(candidates, path.Join(r.layoutsDir, "_default", "baseof.html"))
+
+	for _, candidate := range candidates {
+		content, err := ioutil.ReadFile(candidate)
+		if err == nil {
+			return string(content), nil
+		} else if !os.IsNotExist(err) {
+			return "", fmt.Errorf("rendering error: while reading base layout %s: %v", candidate, err)
+		}
+	}
+
+	content, err := embeddedTemplates.ReadFile(path.Join(templatesRoot, BaseLayoutTemplatePath))
+	if err != nil {
+		return "", fmt.Errorf("rendering error: while reading embedded base layout: %v", err)
 	}
-	return
+	return string(content), nil
 }
 
-func generateTemplateDataContent(n ast.Node, inputFileBytes []byte) string {
+func generateTemplateDataContent(n ast.Node, inputFileBytes []byte, siteConfig map[string]interface{}, sink Sink) (string, error) {
 	switch n.Kind() {
 	case ast.KindText:
 		text := n.(*ast.Text)
@@ -177,19 +478,35 @@ func generateTemplateDataContent(n ast.Node, inputFileBytes []byte) string {
 		} else {
 			content = fmt.Sprintf("%s<br/>\n", content)
 		}
-		return content
+		return content, nil
 	case ast.KindFencedCodeBlock:
 		fencedCodeBlock := n.(*ast.FencedCodeBlock)
-		content := ""
+		var sourceBuilder strings.Builder
 		l := fencedCodeBlock.Lines().Len()
 		for i := 0; i < l; i++ {
 			line := fencedCodeBlock.Lines().At(i)
-			content = fmt.Sprintf("%s%s", content, line.Value(inputFileBytes))
+			sourceBuilder.Write(line.Value(inputFileBytes))
+		}
+		source := sourceBuilder.String()
+
+		language := string(fencedCodeBlock.Language(inputFileBytes))
+		if language == "" || lexers.Get(language) == nil {
+			return htmlstd.EscapeString(source), nil
+		}
+
+		var info string
+		if fencedCodeBlock.Info != nil {
+			info = string(fencedCodeBlock.Info.Value(inputFileBytes))
 		}
-		// TODO: Add syntax highlighting somehow
-		return content
+		highlightLines := parseHighlightLines(info)
+
+		highlighted, err := highlightFencedCodeBlock(source, language, highlightLines, siteConfig, sink)
+		if err != nil {
+			return "", fmt.Errorf("rendering error: while highlighting fenced code block: %v", err)
+		}
+		return highlighted, nil
 	default:
-		return ""
+		return "", nil
 	}
 }
 
@@ -208,30 +525,64 @@ func generateTemplateDataConfig(n ast.Node) map[string]interface{} {
 	return config
 }
 
-func getTemplateContent(nodeKind ast.NodeKind) (string, error) {
-	if len(KindTemplateMap) == 0 {
-		InitKindTemplateMap()
+// buildHookData extracts the contextual data a render-hook template needs
+// for node kinds listed in hookTemplateRelPath. It returns nil for any other
+// kind.
+func buildHookData(n ast.Node, inputFileBytes []byte) *HookData {
+	switch n.Kind() {
+	case ast.KindLink:
+		link := n.(*ast.Link)
+		destination := string(link.Destination)
+		return &HookData{
+			Destination: destination,
+			Title:       string(link.Title),
+			LinkKind:    classifyLinkDestination(destination),
+		}
+	case ast.KindAutoLink:
+		autoLink := n.(*ast.AutoLink)
+		destination := string(autoLink.URL(inputFileBytes))
+		return &HookData{
+			Destination: destination,
+			Label:       string(autoLink.Label(inputFileBytes)),
+			LinkKind:    classifyLinkDestination(destination),
+		}
+	case ast.KindImage:
+		image := n.(*ast.Image)
+		return &HookData{
+			Destination: string(image.Destination),
+			Title:       string(image.Title),
+		}
+	case ast.KindHeading:
+		return &HookData{
+			Label: string(headingPlainText(n, inputFileBytes)),
+		}
+	default:
+		return nil
 	}
-	templatePath, exists := KindTemplateMap[nodeKind]
-	if !exists {
-		return "", fmt.Errorf("rendering error: node kind (%s) doesn't have a template assigned to it", nodeKind)
+}
+
+// classifyLinkDestination buckets a link's destination the way Hugo's
+// render-link hook does: an absolute URL is "external", a site-rooted path
+// is "internal", and anything else (e.g. a relative path to another content
+// file) is treated as a "ref".
+func classifyLinkDestination(destination string) string {
+	if strings.Contains(destination, "://") || strings.HasPrefix(destination, "//") {
+		return "external"
 	}
-	templateContent, err := ioutil.ReadFile(templatePath)
-	if err != nil {
-		return "", fmt.Errorf("rendering error: while reading template %s: %v", templatePath, err)
+	if strings.HasPrefix(destination, "/") {
+		return "internal"
 	}
-	return string(templateContent), nil
+	return "ref"
 }
 
-func renderTemplateToString(templateContent string, templateData TemplateData) (string, error) {
-	t, err := template.New("document").Parse(templateContent)
-	if err != nil {
-		return "", err
-	}
-	var outputBuffer bytes.Buffer
-	err = t.Execute(&outputBuffer, templateData)
-	if err != nil {
-		return "", err
+// headingPlainText concatenates the text segments directly under a heading,
+// giving render hooks a plain-text label without any inline markup.
+func headingPlainText(n ast.Node, inputFileBytes []byte) []byte {
+	var textBuffer bytes.Buffer
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		if text, ok := child.(*ast.Text); ok {
+			textBuffer.Write(text.Segment.Value(inputFileBytes))
+		}
 	}
-	return outputBuffer.String(), nil
+	return textBuffer.Bytes()
 }
@@ -0,0 +1,94 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	meta "github.com/yuin/goldmark-meta"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// syntheticDocument builds markdown source containing n independent
+// top-level paragraphs (each with some inline nodes of its own), so the
+// resulting AST is comparable in size to a large real-world post.
+func syntheticDocument(n int) []byte {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "Paragraph number %d with some **bold** and _emphasis_ text.\n\n", i)
+	}
+	return []byte(b.String())
+}
+
+func parseSyntheticDocument(n int) ([]byte, ast.Node) {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM, meta.Meta),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+	source := syntheticDocument(n)
+	return source, md.Parser().Parse(text.NewReader(source))
+}
+
+// BenchmarkRenderFragment10kNodes renders a synthetic ~10k-node document end
+// to end, exercising template lookup/caching and the full node walk.
+func BenchmarkRenderFragment10kNodes(b *testing.B) {
+	// Each paragraph parses to 8 nodes: the paragraph, three plain text
+	// runs, and two emphasis nodes with one text child each.
+	source, documentNode := parseSyntheticDocument(1250)
+
+	r, err := NewRenderer(Options{})
+	if err != nil {
+		b.Fatalf("NewRenderer: %v", err)
+	}
+	pageMeta := PageMeta{Title: "Bench", Path: "/bench"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.RenderFragment(pageMeta, documentNode, source, discardSink{}); err != nil {
+			b.Fatalf("RenderFragment: %v", err)
+		}
+	}
+}
+
+// BenchmarkRenderBlocksSequential and BenchmarkRenderBlocksParallel render
+// the same set of top-level blocks via renderChildrenSequential and
+// renderBlocksParallel respectively, to demonstrate the win from rendering
+// independent blocks concurrently.
+func BenchmarkRenderBlocksSequential(b *testing.B) {
+	source, documentNode := parseSyntheticDocument(2500)
+
+	r, err := NewRenderer(Options{})
+	if err != nil {
+		b.Fatalf("NewRenderer: %v", err)
+	}
+	pageMeta := PageMeta{Title: "Bench", Path: "/bench"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.renderChildrenSequential(pageMeta, documentNode.FirstChild(), source, discardSink{}); err != nil {
+			b.Fatalf("renderChildrenSequential: %v", err)
+		}
+	}
+}
+
+func BenchmarkRenderBlocksParallel(b *testing.B) {
+	source, documentNode := parseSyntheticDocument(2500)
+
+	r, err := NewRenderer(Options{})
+	if err != nil {
+		b.Fatalf("NewRenderer: %v", err)
+	}
+	pageMeta := PageMeta{Title: "Bench", Path: "/bench"}
+	blocks := siblingSlice(documentNode.FirstChild())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.renderBlocksParallel(pageMeta, blocks, source, discardSink{}); err != nil {
+			b.Fatalf("renderBlocksParallel: %v", err)
+		}
+	}
+}
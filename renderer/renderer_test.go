@@ -0,0 +1,90 @@
+package renderer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// discardSink is a Sink that throws away everything written to it, for
+// tests and benchmarks that don't care about the rendered output location.
+type discardSink struct{}
+
+func (discardSink) Write(pagePath string, output []byte) error { return nil }
+func (discardSink) WriteFile(name string, output []byte) error { return nil }
+
+func TestRenderWithBaseLayoutPassesBodyAsData(t *testing.T) {
+	r, err := NewRenderer(Options{})
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	pageMeta := PageMeta{Title: "Hi", Description: "desc"}
+	body := `<p>{{ .Config }}</p>`
+
+	output, err := r.renderWithBaseLayout(pageMeta, body)
+	if err != nil {
+		t.Fatalf("renderWithBaseLayout: %v", err)
+	}
+
+	if !strings.Contains(output, body) {
+		t.Errorf("output = %q, want it to contain the literal body %q", output, body)
+	}
+}
+
+func TestBaseLayoutTemplateResolutionOrder(t *testing.T) {
+	layoutsDir := t.TempDir()
+
+	mustWrite := func(relPath, content string) {
+		full := filepath.Join(layoutsDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := ioutil.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	defaultOverride := `{{ define "baseof" }}default-override{{ end }}`
+	sectionOverride := `{{ define "baseof" }}section-override{{ end }}`
+	mustWrite(filepath.Join("_default", "baseof.html"), defaultOverride)
+	mustWrite(filepath.Join("posts", "baseof.html"), sectionOverride)
+
+	r, err := NewRenderer(Options{LayoutsDir: layoutsDir})
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	content, err := r.loadBaseLayoutContent("posts")
+	if err != nil {
+		t.Fatalf("loadBaseLayoutContent(posts): %v", err)
+	}
+	if content != sectionOverride {
+		t.Errorf("loadBaseLayoutContent(posts) = %q, want section override %q", content, sectionOverride)
+	}
+
+	content, err = r.loadBaseLayoutContent("other-section")
+	if err != nil {
+		t.Fatalf("loadBaseLayoutContent(other-section): %v", err)
+	}
+	if content != defaultOverride {
+		t.Errorf("loadBaseLayoutContent(other-section) = %q, want default override %q", content, defaultOverride)
+	}
+}
+
+func TestBaseLayoutTemplateFallsBackToEmbedded(t *testing.T) {
+	r, err := NewRenderer(Options{LayoutsDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	content, err := r.loadBaseLayoutContent("")
+	if err != nil {
+		t.Fatalf("loadBaseLayoutContent(\"\"): %v", err)
+	}
+	if !strings.Contains(content, `{{ define "baseof" }}`) {
+		t.Errorf("loadBaseLayoutContent(\"\") = %q, want embedded default", content)
+	}
+}
@@ -0,0 +1,82 @@
+package renderer
+
+import (
+	"embed"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+//go:embed templates/block/*.tmpl.html templates/inline/*.tmpl.html templates/base/*.tmpl.html
+var embeddedTemplates embed.FS
+
+// templatesRoot is the directory inside embeddedTemplates that the paths
+// below are relative to.
+const templatesRoot = "templates"
+
+// BaseLayoutTemplatePath is the embedded default for the HTML shell pages
+// are composed into. See (*Renderer).loadBaseLayoutContent for the full
+// resolution order, which lets a site override it per-section.
+const BaseLayoutTemplatePath = "base/baseof.tmpl.html"
+
+const (
+	// Block Templates
+	DocumentTemplatePath        = "block/document.tmpl.html"
+	TextBlockTemplatePath       = "block/text-block.tmpl.html"
+	ParagraphTemplatePath       = "block/paragraph.tmpl.html"
+	HeadingTemplatePath         = "block/heading.tmpl.html"
+	ThematicBreakTemplatePath   = "block/thematic-break.tmpl.html"
+	CodeBlockTemplatePath       = "block/code-block.tmpl.html"
+	FencedCodeBlockTemplatePath = "block/fenced-code-block.tmpl.html"
+	BlockquoteTemplatePath      = "block/blockquote.tmpl.html"
+	ListTemplatePath            = "block/list.tmpl.html"
+	ListItemTemplatePath        = "block/list-item.tmpl.html"
+	HTMLBlockTemplatePath       = "block/html-block.tmpl.html"
+
+	// Inline Templates
+	TextTemplatePath     = "inline/text.tmpl.html"
+	StringTemplatePath   = "inline/string.tmpl.html"
+	CodeSpanTemplatePath = "inline/code-span.tmpl.html"
+	EmphasisTemplatePath = "inline/emphasis.tmpl.html"
+	LinkTemplatePath     = "inline/link.tmpl.html"
+	ImageTemplatePath    = "inline/image.tmpl.html"
+	AutoLinkTemplatePath = "inline/auto-link.tmpl.html"
+	RawHTMLTemplatePath  = "inline/raw-html.tmpl.html"
+)
+
+// KindTemplateMap maps AST node kinds to the relative path of their
+// template, under both LayoutsDir (for overrides) and embeddedTemplates
+// (for the built-in default).
+var KindTemplateMap map[ast.NodeKind]string
+
+// hookTemplateRelPath maps node kinds that support render hooks to the
+// relative path, under a Renderer's layouts directory, of their hook
+// template. Unlike KindTemplateMap, hooks have no embedded default.
+var hookTemplateRelPath = map[ast.NodeKind]string{
+	ast.KindLink:     "_markup/render-link.html",
+	ast.KindAutoLink: "_markup/render-autolink.html",
+	ast.KindImage:    "_markup/render-image.html",
+	ast.KindHeading:  "_markup/render-heading.html",
+}
+
+func InitKindTemplateMap() {
+	KindTemplateMap = make(map[ast.NodeKind]string)
+	KindTemplateMap[ast.KindDocument] = DocumentTemplatePath
+	KindTemplateMap[ast.KindTextBlock] = TextBlockTemplatePath
+	KindTemplateMap[ast.KindParagraph] = ParagraphTemplatePath
+	KindTemplateMap[ast.KindHeading] = HeadingTemplatePath
+	KindTemplateMap[ast.KindThematicBreak] = ThematicBreakTemplatePath
+	KindTemplateMap[ast.KindCodeBlock] = CodeBlockTemplatePath
+	KindTemplateMap[ast.KindFencedCodeBlock] = FencedCodeBlockTemplatePath
+	KindTemplateMap[ast.KindBlockquote] = BlockquoteTemplatePath
+	KindTemplateMap[ast.KindList] = ListTemplatePath
+	KindTemplateMap[ast.KindListItem] = ListItemTemplatePath
+	KindTemplateMap[ast.KindHTMLBlock] = HTMLBlockTemplatePath
+	KindTemplateMap[ast.KindText] = TextTemplatePath
+	KindTemplateMap[ast.KindString] = StringTemplatePath
+	KindTemplateMap[ast.KindCodeSpan] = CodeSpanTemplatePath
+	KindTemplateMap[ast.KindEmphasis] = EmphasisTemplatePath
+	KindTemplateMap[ast.KindLink] = LinkTemplatePath
+	KindTemplateMap[ast.KindImage] = ImageTemplatePath
+	KindTemplateMap[ast.KindAutoLink] = AutoLinkTemplatePath
+	KindTemplateMap[ast.KindRawHTML] = RawHTMLTemplatePath
+}
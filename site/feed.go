@@ -0,0 +1,106 @@
+package site
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/BradHacker/blogdown/content"
+)
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// writeSitemap emits sitemap.xml via sink, one <url> per page, using
+// siteConfig["baseURL"] to turn page paths into absolute URLs.
+func writeSitemap(pages []page, siteConfig map[string]interface{}, sink content.Sink) error {
+	baseURL, _ := siteConfig["baseURL"].(string)
+
+	urlSet := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, p := range pages {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:     baseURL + p.Meta.Path,
+			LastMod: p.Date.Format(time.RFC3339),
+		})
+	}
+
+	return writeXMLFile("sitemap.xml", urlSet, sink)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Summary atomSummary `xml:"summary"`
+}
+
+type atomSummary struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// writeFeed emits feed.atom via sink, one <entry> per page, newest first.
+// siteConfig["baseURL"] and siteConfig["title"] fill in the feed's own
+// link/id and title.
+func writeFeed(pages []page, siteConfig map[string]interface{}, sink content.Sink) error {
+	baseURL, _ := siteConfig["baseURL"].(string)
+	siteTitle, _ := siteConfig["title"].(string)
+
+	feed := atomFeed{
+		XMLNS: "http://www.w3.org/2005/Atom",
+		Title: siteTitle,
+		ID:    baseURL,
+		Link:  atomLink{Href: baseURL},
+	}
+	if len(pages) > 0 {
+		feed.Updated = pages[0].Date.Format(time.RFC3339)
+	}
+	for _, p := range pages {
+		pageURL := baseURL + p.Meta.Path
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   p.Meta.Title,
+			ID:      pageURL,
+			Updated: p.Date.Format(time.RFC3339),
+			Link:    atomLink{Href: pageURL},
+			Summary: atomSummary{Type: "html", Text: p.Summary},
+		})
+	}
+
+	return writeXMLFile("feed.atom", feed, sink)
+}
+
+func writeXMLFile(name string, v interface{}, sink content.Sink) error {
+	output, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	contents := append([]byte(xml.Header), output...)
+	if err := sink.WriteFile(name, contents); err != nil {
+		return fmt.Errorf("site: while writing %s: %v", name, err)
+	}
+	return nil
+}
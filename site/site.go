@@ -0,0 +1,149 @@
+package site
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/BradHacker/blogdown/content"
+	"github.com/BradHacker/blogdown/parser"
+	"github.com/BradHacker/blogdown/renderer"
+	"github.com/yuin/goldmark/ast"
+)
+
+// page is everything the sitemap and feed generators need about a single
+// rendered page.
+type page struct {
+	Meta    renderer.PageMeta
+	Date    time.Time
+	Summary string
+}
+
+// dateLayouts are tried in order when parsing a page's "date" metadata field.
+var dateLayouts = []string{time.RFC3339, "2006-01-02", "2006-01-02T15:04:05"}
+
+// BuildDir is a convenience wrapper around Build for the common case of a
+// site whose content lives in, and whose output should go to, directories
+// on disk.
+func BuildDir(contentDir, outDir string, siteConfig map[string]interface{}) error {
+	return Build(content.NewFilesystemSource(contentDir), content.NewFilesystemSink(outDir), siteConfig)
+}
+
+// Build lists every item in source, renders each one with the renderer
+// package into sink, and emits a sitemap and Atom feed describing the
+// resulting site into sink as well. siteConfig is passed through to the
+// renderer unchanged, and is also consulted here for "baseURL" and "title".
+//
+// A single Renderer is built and reused across every page, so the template
+// set is only loaded once per build rather than once per page.
+func Build(source content.Source, sink content.Sink, siteConfig map[string]interface{}) error {
+	p := parser.NewParser()
+	r, err := renderer.NewRenderer(renderer.Options{SiteConfig: siteConfig})
+	if err != nil {
+		return fmt.Errorf("site: while loading templates: %v", err)
+	}
+
+	items, err := source.List()
+	if err != nil {
+		return fmt.Errorf("site: while listing content: %v", err)
+	}
+
+	var pages []page
+	for _, item := range items {
+		sourceMetadata, body, err := source.Open(item.ID)
+		if err != nil {
+			return fmt.Errorf("site: while opening %s: %v", item.ID, err)
+		}
+
+		parsedMetadata, documentNode, fileBytes, err := p.Parse(body)
+		if err != nil {
+			return fmt.Errorf("site: while parsing %s: %v", item.ID, err)
+		}
+		metadata := mergeMetadata(sourceMetadata, parsedMetadata)
+
+		if err := r.RenderPage(metadata, documentNode, fileBytes, sink); err != nil {
+			return fmt.Errorf("site: while rendering %s: %v", item.ID, err)
+		}
+
+		pageMeta, err := renderer.NewPageMeta(metadata)
+		if err != nil {
+			return fmt.Errorf("site: while reading metadata for %s: %v", item.ID, err)
+		}
+
+		summary, err := renderSummary(r, pageMeta, documentNode, fileBytes, sink)
+		if err != nil {
+			return fmt.Errorf("site: while summarizing %s: %v", item.ID, err)
+		}
+
+		pages = append(pages, page{
+			Meta:    pageMeta,
+			Date:    pageDate(metadata, item),
+			Summary: summary,
+		})
+	}
+
+	sort.Slice(pages, func(i, j int) bool {
+		return pages[i].Date.After(pages[j].Date)
+	})
+
+	if err := writeSitemap(pages, siteConfig, sink); err != nil {
+		return err
+	}
+	return writeFeed(pages, siteConfig, sink)
+}
+
+// mergeMetadata combines a source's own metadata (e.g. a CMS's frontmatter
+// field) with the metadata parsed out of the markdown body, with parsed
+// metadata taking precedence since it's closer to the content itself.
+func mergeMetadata(sourceMetadata, parsedMetadata map[string]interface{}) map[string]interface{} {
+	if len(sourceMetadata) == 0 {
+		return parsedMetadata
+	}
+	merged := make(map[string]interface{}, len(sourceMetadata)+len(parsedMetadata))
+	for k, v := range sourceMetadata {
+		merged[k] = v
+	}
+	for k, v := range parsedMetadata {
+		merged[k] = v
+	}
+	return merged
+}
+
+// renderSummary renders the page's first paragraph in isolation, for use as
+// a feed entry's HTML summary.
+func renderSummary(r *renderer.Renderer, pageMeta renderer.PageMeta, documentNode ast.Node, fileBytes []byte, sink content.Sink) (string, error) {
+	firstParagraph := findFirstParagraph(documentNode)
+	if firstParagraph == nil {
+		return "", nil
+	}
+	return r.RenderFragment(pageMeta, firstParagraph, fileBytes, sink)
+}
+
+// findFirstParagraph depth-first searches for the first paragraph node in
+// the document, since front matter can be followed by other block types
+// (e.g. a heading) before the first real paragraph.
+func findFirstParagraph(n ast.Node) ast.Node {
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		if child.Kind() == ast.KindParagraph {
+			return child
+		}
+		if found := findFirstParagraph(child); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// pageDate resolves a page's publish date from a "date" metadata field,
+// falling back to the source item's modification time.
+func pageDate(metadata map[string]interface{}, item content.Item) time.Time {
+	if dateInt, exists := metadata["date"]; exists {
+		dateString := fmt.Sprintf("%v", dateInt)
+		for _, layout := range dateLayouts {
+			if parsed, err := time.Parse(layout, dateString); err == nil {
+				return parsed
+			}
+		}
+	}
+	return item.ModTime
+}
@@ -0,0 +1,68 @@
+package site
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BradHacker/blogdown/content"
+)
+
+func TestPageDatePrefersDateMetadataOverModTime(t *testing.T) {
+	modTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := content.Item{ID: "post.md", ModTime: modTime}
+
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		want     time.Time
+	}{
+		{
+			name:     "RFC3339",
+			metadata: map[string]interface{}{"date": "2023-05-17T10:00:00Z"},
+			want:     time.Date(2023, 5, 17, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "date only",
+			metadata: map[string]interface{}{"date": "2023-05-17"},
+			want:     time.Date(2023, 5, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "no timezone",
+			metadata: map[string]interface{}{"date": "2023-05-17T10:00:00"},
+			want:     time.Date(2023, 5, 17, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "missing falls back to ModTime",
+			metadata: map[string]interface{}{},
+			want:     modTime,
+		},
+		{
+			name:     "unparseable falls back to ModTime",
+			metadata: map[string]interface{}{"date": "not a date"},
+			want:     modTime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pageDate(tt.metadata, item)
+			if !got.Equal(tt.want) {
+				t.Errorf("pageDate(%v) = %v, want %v", tt.metadata, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeMetadataParsedTakesPrecedence(t *testing.T) {
+	source := map[string]interface{}{"title": "from source", "author": "source author"}
+	parsed := map[string]interface{}{"title": "from body"}
+
+	merged := mergeMetadata(source, parsed)
+
+	if merged["title"] != "from body" {
+		t.Errorf("merged[title] = %v, want %q", merged["title"], "from body")
+	}
+	if merged["author"] != "source author" {
+		t.Errorf("merged[author] = %v, want %q", merged["author"], "source author")
+	}
+}